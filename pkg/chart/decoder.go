@@ -0,0 +1,129 @@
+package chart
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/BurntSushi/toml"
+	"github.com/ghodss/yaml"
+)
+
+// ValuesDecoder parses raw file data into a Values.
+//
+// Implementations are registered against a file extension with
+// RegisterValuesDecoder so that ReadValuesFile can dispatch to them without
+// the caller needing to know the format in advance.
+type ValuesDecoder interface {
+	Decode(data []byte) (Values, error)
+}
+
+// ValuesDecoderFunc adapts a plain function to a ValuesDecoder.
+type ValuesDecoderFunc func(data []byte) (Values, error)
+
+// Decode calls f(data).
+func (f ValuesDecoderFunc) Decode(data []byte) (Values, error) {
+	return f(data)
+}
+
+var (
+	decodersMu sync.RWMutex
+	decoders   = map[string]ValuesDecoder{
+		".toml": tomlValuesDecoder{},
+		".yaml": ValuesDecoderFunc(decodeYAMLValues),
+		".yml":  ValuesDecoderFunc(decodeYAMLValues),
+		".json": ValuesDecoderFunc(decodeJSONValues),
+		".env":  ValuesDecoderFunc(decodeDotenvValues),
+	}
+)
+
+// RegisterValuesDecoder registers dec as the ValuesDecoder that
+// ReadValuesFile uses for files with the given extension (including the
+// leading dot, e.g. ".hcl"). Registering against an extension already in
+// use replaces its decoder.
+func RegisterValuesDecoder(ext string, dec ValuesDecoder) {
+	decodersMu.Lock()
+	defer decodersMu.Unlock()
+	decoders[ext] = dec
+}
+
+// decoderForFile returns the registered ValuesDecoder for filename's
+// extension, falling back to TOML if none is registered.
+func decoderForFile(filename string) ValuesDecoder {
+	decodersMu.RLock()
+	defer decodersMu.RUnlock()
+	if dec, ok := decoders[strings.ToLower(filepath.Ext(filename))]; ok {
+		return dec
+	}
+	return tomlValuesDecoder{}
+}
+
+// tomlValuesDecoder is the original Values format.
+type tomlValuesDecoder struct{}
+
+func (tomlValuesDecoder) Decode(data []byte) (Values, error) {
+	out := map[string]interface{}{}
+	err := toml.Unmarshal(data, out)
+	return out, err
+}
+
+// decodeYAMLValues parses data as YAML. It goes by way of ghodss/yaml,
+// which round-trips through JSON, so maps come out keyed by string (as
+// encoding/json and gojsonschema expect) rather than yaml.v2's
+// map[interface{}]interface{}.
+func decodeYAMLValues(data []byte) (Values, error) {
+	out := map[string]interface{}{}
+	if err := yaml.Unmarshal(data, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func decodeJSONValues(data []byte) (Values, error) {
+	out := map[string]interface{}{}
+	if len(bytes.TrimSpace(data)) == 0 {
+		return out, nil
+	}
+	err := json.Unmarshal(data, &out)
+	return out, err
+}
+
+// decodeDotenvValues parses data as a flat KEY=VALUE file, one assignment
+// per line. Blank lines and lines starting with "#" are ignored; values may
+// be wrapped in matching single or double quotes.
+func decodeDotenvValues(data []byte) (Values, error) {
+	out := map[string]interface{}{}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		parts := strings.SplitN(line, "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("dotenv: invalid line %q, expected KEY=VALUE", line)
+		}
+		key := strings.TrimSpace(parts[0])
+		val := unquoteDotenvValue(strings.TrimSpace(parts[1]))
+		out[key] = val
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func unquoteDotenvValue(v string) string {
+	if len(v) >= 2 {
+		if (v[0] == '"' && v[len(v)-1] == '"') || (v[0] == '\'' && v[len(v)-1] == '\'') {
+			return v[1 : len(v)-1]
+		}
+	}
+	return v
+}
@@ -0,0 +1,48 @@
+package chart
+
+import "testing"
+
+func TestReadValuesWith_BuiltinDecoders(t *testing.T) {
+	tests := []struct {
+		decoder ValuesDecoder
+		data    string
+		key     string
+		want    interface{}
+	}{
+		{tomlValuesDecoder{}, "replicaCount = 3\n", "replicaCount", int64(3)},
+		{ValuesDecoderFunc(decodeYAMLValues), "replicaCount: 3\n", "replicaCount", float64(3)},
+		{ValuesDecoderFunc(decodeJSONValues), `{"replicaCount": 3}`, "replicaCount", float64(3)},
+		{ValuesDecoderFunc(decodeDotenvValues), "REPLICA_COUNT=3\n", "REPLICA_COUNT", "3"},
+	}
+
+	for _, tt := range tests {
+		v, err := ReadValuesWith([]byte(tt.data), tt.decoder)
+		if err != nil {
+			t.Fatalf("decoding %q: %s", tt.data, err)
+		}
+		if v[tt.key] != tt.want {
+			t.Errorf("decoding %q: got %v (%T), want %v (%T)", tt.data, v[tt.key], v[tt.key], tt.want, tt.want)
+		}
+	}
+}
+
+func TestRegisterValuesDecoder(t *testing.T) {
+	RegisterValuesDecoder(".myfmt", ValuesDecoderFunc(func(data []byte) (Values, error) {
+		return Values{"ok": true}, nil
+	}))
+
+	dec := decoderForFile("values.myfmt")
+	v, err := dec.Decode(nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if v["ok"] != true {
+		t.Errorf("expected the registered decoder to be used, got %v", v)
+	}
+}
+
+func TestDecodeDotenvValues_InvalidLine(t *testing.T) {
+	if _, err := decodeDotenvValues([]byte("not-a-valid-line\n")); err == nil {
+		t.Fatal("expected an error for a line without '='")
+	}
+}
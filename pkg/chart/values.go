@@ -1,12 +1,15 @@
 package chart
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"io/ioutil"
 	"strings"
 
 	"github.com/BurntSushi/toml"
+	"github.com/xeipuuv/gojsonschema"
 )
 
 // ErrNoTable indicates that a chart does not have a matching table.
@@ -15,7 +18,7 @@ var ErrNoTable = errors.New("no table")
 // Values represents a collection of chart values.
 type Values map[string]interface{}
 
-// Table gets a table (TOML subsection) from a Values object.
+// Table gets a table (configuration subsection) from a Values object.
 //
 // The table is returned as a Values.
 //
@@ -46,6 +49,44 @@ func (v Values) Encode(w io.Writer) error {
 	return toml.NewEncoder(w).Encode(v)
 }
 
+// ValidationError reports every violation found while validating Values
+// against a JSON Schema, not just the first.
+type ValidationError struct {
+	Errors []string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("values don't meet the specifications of the schema(s): %s", strings.Join(e.Errors, ", "))
+}
+
+// Validate checks v against schema, a JSON Schema (draft-07) document, and
+// reports every violation found via a *ValidationError.
+//
+// Values is decoded by a ValuesDecoder (TOML by default) into
+// map[string]interface{}, so v is round-tripped through JSON first to present
+// the validator with canonical JSON types rather than whatever shapes the
+// decoder produced.
+func (v Values) Validate(schema []byte) error {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("encoding values for schema validation: %s", err)
+	}
+
+	result, err := gojsonschema.Validate(gojsonschema.NewBytesLoader(schema), gojsonschema.NewBytesLoader(raw))
+	if err != nil {
+		return fmt.Errorf("validating values against schema: %s", err)
+	}
+	if result.Valid() {
+		return nil
+	}
+
+	errs := make([]string, 0, len(result.Errors()))
+	for _, e := range result.Errors() {
+		errs = append(errs, e.String())
+	}
+	return &ValidationError{Errors: errs}
+}
+
 func tableLookup(v Values, simple string) (Values, error) {
 	v2, ok := v[simple]
 	if !ok {
@@ -59,17 +100,25 @@ func tableLookup(v Values, simple string) (Values, error) {
 }
 
 // ReadValues will parse TOML byte data into a Values.
+//
+// To parse another format, use ReadValuesWith with the ValuesDecoder for
+// that format.
 func ReadValues(data []byte) (Values, error) {
-	out := map[string]interface{}{}
-	err := toml.Unmarshal(data, out)
-	return out, err
+	return ReadValuesWith(data, tomlValuesDecoder{})
+}
+
+// ReadValuesWith parses data into a Values using dec.
+func ReadValuesWith(data []byte, dec ValuesDecoder) (Values, error) {
+	return dec.Decode(data)
 }
 
-// ReadValuesFile will parse a TOML file into a Values.
+// ReadValuesFile will parse a values file into a Values, choosing a
+// ValuesDecoder by the file's extension. Extensions with no registered
+// decoder are parsed as TOML, the original values format.
 func ReadValuesFile(filename string) (Values, error) {
 	data, err := ioutil.ReadFile(filename)
 	if err != nil {
 		return map[string]interface{}{}, err
 	}
-	return ReadValues(data)
+	return ReadValuesWith(data, decoderForFile(filename))
 }
@@ -0,0 +1,39 @@
+package chart
+
+import "testing"
+
+func TestValues_Validate(t *testing.T) {
+	schema := []byte(`{
+		"$schema": "http://json-schema.org/draft-07/schema#",
+		"type": "object",
+		"properties": {
+			"replicaCount": {"type": "integer", "minimum": 1}
+		},
+		"required": ["replicaCount"]
+	}`)
+
+	v := Values{"replicaCount": 3}
+	if err := v.Validate(schema); err != nil {
+		t.Errorf("expected valid values, got error: %s", err)
+	}
+}
+
+func TestValues_Validate_Errors(t *testing.T) {
+	schema := []byte(`{
+		"$schema": "http://json-schema.org/draft-07/schema#",
+		"type": "object",
+		"properties": {
+			"replicaCount": {"type": "integer", "minimum": 1}
+		},
+		"required": ["replicaCount"]
+	}`)
+
+	v := Values{"replicaCount": "not-a-number"}
+	err := v.Validate(schema)
+	if err == nil {
+		t.Fatal("expected a validation error")
+	}
+	if _, ok := err.(*ValidationError); !ok {
+		t.Errorf("expected *ValidationError, got %T", err)
+	}
+}
@@ -0,0 +1,94 @@
+package chart
+
+import (
+	"fmt"
+	"regexp"
+
+	"k8s.io/helm/pkg/vault"
+)
+
+// vaultRefPattern matches a "${vault:secret/path#field}" placeholder,
+// capturing the "secret/path#field" reference.
+var vaultRefPattern = regexp.MustCompile(`\$\{vault:([^}]+)\}`)
+
+// VaultValuesDecoder wraps another ValuesDecoder, resolving any
+// "${vault:secret/path#field}" placeholders left in its decoded Values
+// against a vault.SecretResolver. Wrap Resolver in vault.NewCachingResolver
+// to avoid fetching the same reference once per render.
+type VaultValuesDecoder struct {
+	Inner    ValuesDecoder
+	Resolver vault.SecretResolver
+}
+
+// Decode runs data through d.Inner, then resolves every vault placeholder
+// found in the result.
+func (d *VaultValuesDecoder) Decode(data []byte) (Values, error) {
+	v, err := d.Inner.Decode(data)
+	if err != nil {
+		return nil, err
+	}
+	if err := resolveVaultRefs(map[string]interface{}(v), d.Resolver); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+func resolveVaultRefs(m map[string]interface{}, resolver vault.SecretResolver) error {
+	for k, val := range m {
+		resolved, err := resolveVaultValue(val, resolver)
+		if err != nil {
+			return fmt.Errorf("resolving vault secret for %q: %s", k, err)
+		}
+		m[k] = resolved
+	}
+	return nil
+}
+
+// resolveVaultValue resolves vault placeholders anywhere inside val: in a
+// string directly, or recursively through the maps and slices that a
+// decoded values.yaml is built out of (e.g. an "env:" list of maps).
+func resolveVaultValue(val interface{}, resolver vault.SecretResolver) (interface{}, error) {
+	switch vv := val.(type) {
+	case string:
+		if !vaultRefPattern.MatchString(vv) {
+			return vv, nil
+		}
+		return resolveVaultString(vv, resolver)
+	case map[string]interface{}:
+		if err := resolveVaultRefs(vv, resolver); err != nil {
+			return nil, err
+		}
+		return vv, nil
+	case []interface{}:
+		for i, item := range vv {
+			resolved, err := resolveVaultValue(item, resolver)
+			if err != nil {
+				return nil, err
+			}
+			vv[i] = resolved
+		}
+		return vv, nil
+	default:
+		return val, nil
+	}
+}
+
+func resolveVaultString(s string, resolver vault.SecretResolver) (string, error) {
+	var resolveErr error
+	out := vaultRefPattern.ReplaceAllStringFunc(s, func(match string) string {
+		if resolveErr != nil {
+			return match
+		}
+		ref := vaultRefPattern.FindStringSubmatch(match)[1]
+		val, err := resolver.Resolve(ref)
+		if err != nil {
+			resolveErr = err
+			return match
+		}
+		return val
+	})
+	if resolveErr != nil {
+		return "", resolveErr
+	}
+	return out, nil
+}
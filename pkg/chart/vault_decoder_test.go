@@ -0,0 +1,90 @@
+package chart
+
+import (
+	"fmt"
+	"testing"
+
+	"k8s.io/helm/pkg/vault"
+)
+
+type fakeSecretResolver struct {
+	calls   int
+	secrets map[string]string
+}
+
+func (f *fakeSecretResolver) Resolve(ref string) (string, error) {
+	f.calls++
+	v, ok := f.secrets[ref]
+	if !ok {
+		return "", fmt.Errorf("no such secret: %s", ref)
+	}
+	return v, nil
+}
+
+func TestVaultValuesDecoder(t *testing.T) {
+	resolver := &fakeSecretResolver{secrets: map[string]string{"secret/db#password": "hunter2"}}
+
+	dec := &VaultValuesDecoder{
+		Inner:    ValuesDecoderFunc(decodeYAMLValues),
+		Resolver: resolver,
+	}
+
+	v, err := dec.Decode([]byte("db:\n  password: ${vault:secret/db#password}\n"))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	db, ok := v["db"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("expected db to be a nested map, got %T", v["db"])
+	}
+	if db["password"] != "hunter2" {
+		t.Errorf("expected resolved password, got %v", db["password"])
+	}
+}
+
+func TestVaultValuesDecoder_ResolvesRefsInsideLists(t *testing.T) {
+	resolver := &fakeSecretResolver{secrets: map[string]string{"secret/db#password": "hunter2"}}
+
+	dec := &VaultValuesDecoder{
+		Inner:    ValuesDecoderFunc(decodeYAMLValues),
+		Resolver: resolver,
+	}
+
+	data := []byte("env:\n  - name: DB_PASS\n    value: ${vault:secret/db#password}\n")
+	v, err := dec.Decode(data)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	env, ok := v["env"].([]interface{})
+	if !ok || len(env) != 1 {
+		t.Fatalf("expected env to be a one-item list, got %#v", v["env"])
+	}
+	entry, ok := env[0].(map[string]interface{})
+	if !ok {
+		t.Fatalf("expected env[0] to be a map, got %T", env[0])
+	}
+	if entry["value"] != "hunter2" {
+		t.Errorf("expected resolved password inside list, got %v", entry["value"])
+	}
+}
+
+func TestVaultValuesDecoder_CachingResolverFetchesOnce(t *testing.T) {
+	resolver := &fakeSecretResolver{secrets: map[string]string{"secret/db#password": "hunter2"}}
+	cached := vault.NewCachingResolver(resolver)
+
+	dec := &VaultValuesDecoder{
+		Inner:    ValuesDecoderFunc(decodeYAMLValues),
+		Resolver: cached,
+	}
+
+	data := []byte("db:\n  password: ${vault:secret/db#password}\n  passwordAgain: ${vault:secret/db#password}\n")
+	if _, err := dec.Decode(data); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if resolver.calls != 1 {
+		t.Errorf("expected the underlying resolver to be called once, got %d", resolver.calls)
+	}
+}
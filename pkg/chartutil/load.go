@@ -13,15 +13,34 @@ import (
 	"strings"
 
 	"github.com/golang/protobuf/ptypes/any"
+	"golang.org/x/crypto/openpgp"
 
 	"k8s.io/helm/pkg/proto/hapi/chart"
+	"k8s.io/helm/pkg/registry"
 )
 
-// Load takes a string name, tries to resolve it to a file or directory, and then loads it.
+// DefaultRegistryClient is the RegistryClient used by Load to resolve
+// "oci://" chart references. It is nil until a caller sets it, since
+// chartutil has no opinion on which OCI backend to use.
+var DefaultRegistryClient registry.RegistryClient
+
+// ociPrefix marks a chart reference as living in an OCI artifact registry
+// rather than on the local filesystem.
+const ociPrefix = "oci://"
+
+// Load takes a string name, tries to resolve it to a file, directory, or
+// OCI reference, and then loads it.
 //
 // This is the preferred way to load a chart. It will discover the chart encoding
 // and hand off to the appropriate chart reader.
 func Load(name string) (*chart.Chart, error) {
+	if strings.HasPrefix(name, ociPrefix) {
+		if DefaultRegistryClient == nil {
+			return nil, errors.New("no registry client configured for oci:// chart references")
+		}
+		return LoadOCI(strings.TrimPrefix(name, ociPrefix), DefaultRegistryClient)
+	}
+
 	fi, err := os.Stat(name)
 	if err != nil {
 		return nil, err
@@ -38,8 +57,67 @@ type afile struct {
 	data []byte
 }
 
-// LoadArchive loads from a reader containing a compressed tar archive.
+// LoadOptions bounds the resources consumed while reading a chart archive,
+// guarding against zip-bomb style attacks from untrusted sources.
+type LoadOptions struct {
+	// MaxFileSize caps the decompressed size of any single file in the
+	// archive. Zero means unbounded.
+	MaxFileSize int64
+	// MaxTotalSize caps the cumulative decompressed size of all files in
+	// the archive. Zero means unbounded.
+	MaxTotalSize int64
+	// MaxFiles caps the number of files in the archive. Zero means
+	// unbounded.
+	MaxFiles int
+	// MaxSubchartDepth caps how many levels of charts/ nesting loadFiles
+	// will recurse into. Zero means unbounded.
+	MaxSubchartDepth int
+}
+
+// DefaultLoadOptions returns the limits LoadArchive applies when no
+// explicit LoadOptions are supplied.
+func DefaultLoadOptions() LoadOptions {
+	return LoadOptions{
+		MaxFileSize:      20 * 1024 * 1024,
+		MaxTotalSize:     100 * 1024 * 1024,
+		MaxFiles:         10000,
+		MaxSubchartDepth: 20,
+	}
+}
+
+var (
+	// ErrArchiveTooLarge indicates a chart archive's decompressed size
+	// exceeds MaxFileSize or MaxTotalSize.
+	ErrArchiveTooLarge = errors.New("chart archive exceeds maximum allowed size")
+	// ErrTooManyFiles indicates a chart archive contains more files than
+	// MaxFiles allows.
+	ErrTooManyFiles = errors.New("chart archive contains too many files")
+	// ErrPathEscape indicates a chart archive entry's path climbs outside
+	// of the chart root via ".." or is absolute.
+	ErrPathEscape = errors.New("chart archive entry escapes chart root")
+	// ErrSubchartTooDeep indicates a chart nests subcharts more deeply
+	// than MaxSubchartDepth allows.
+	ErrSubchartTooDeep = errors.New("chart nests subcharts too deeply")
+)
+
+// LoadArchive loads from a reader containing a compressed tar archive,
+// applying the limits from DefaultLoadOptions.
 func LoadArchive(in io.Reader) (*chart.Chart, error) {
+	return LoadArchiveWithOptions(in, DefaultLoadOptions())
+}
+
+// LoadArchiveWithOptions loads from a reader containing a compressed tar
+// archive, enforcing opts as entries are streamed out of the tarball. Each
+// entry is read through a bounded reader so a maliciously crafted gzip
+// stream cannot exhaust memory before its declared size is checked.
+func LoadArchiveWithOptions(in io.Reader, opts LoadOptions) (*chart.Chart, error) {
+	return loadArchiveWithOptions(in, opts, 0)
+}
+
+// loadArchiveWithOptions is LoadArchiveWithOptions with an explicit
+// subchart depth, so that a .tgz subchart nested inside another archive can
+// carry its depth forward instead of restarting the count at zero.
+func loadArchiveWithOptions(in io.Reader, opts LoadOptions, depth int) (*chart.Chart, error) {
 	unzipped, err := gzip.NewReader(in)
 	if err != nil {
 		return &chart.Chart{}, err
@@ -48,8 +126,8 @@ func LoadArchive(in io.Reader) (*chart.Chart, error) {
 
 	files := []*afile{}
 	tr := tar.NewReader(unzipped)
+	var total int64
 	for {
-		b := bytes.NewBuffer(nil)
 		hd, err := tr.Next()
 		if err == io.EOF {
 			break
@@ -64,25 +142,66 @@ func LoadArchive(in io.Reader) (*chart.Chart, error) {
 			continue
 		}
 
+		if opts.MaxFiles > 0 && len(files) >= opts.MaxFiles {
+			return &chart.Chart{}, ErrTooManyFiles
+		}
+
 		parts := strings.Split(hd.Name, "/")
 		n := strings.Join(parts[1:], "/")
+		if err := checkArchivePath(n); err != nil {
+			return &chart.Chart{}, err
+		}
 
-		if _, err := io.Copy(b, tr); err != nil {
+		b := bytes.NewBuffer(nil)
+		limit := opts.MaxFileSize
+		if limit <= 0 {
+			limit = hd.Size + 1
+		}
+		written, err := io.Copy(b, io.LimitReader(tr, limit+1))
+		if err != nil {
 			return &chart.Chart{}, err
 		}
+		if opts.MaxFileSize > 0 && written > opts.MaxFileSize {
+			return &chart.Chart{}, ErrArchiveTooLarge
+		}
+
+		total += written
+		if opts.MaxTotalSize > 0 && total > opts.MaxTotalSize {
+			return &chart.Chart{}, ErrArchiveTooLarge
+		}
 
 		files = append(files, &afile{name: n, data: b.Bytes()})
-		b.Reset()
 	}
 
 	if len(files) == 0 {
 		return nil, errors.New("no files in chart archive")
 	}
 
-	return loadFiles(files)
+	return loadFiles(files, opts, depth)
+}
+
+// checkArchivePath rejects archive entry paths that climb outside of the
+// chart root via ".." or are rooted absolutely.
+func checkArchivePath(n string) error {
+	if filepath.IsAbs(n) {
+		return ErrPathEscape
+	}
+	clean := filepath.Clean(n)
+	if clean == ".." || strings.HasPrefix(clean, ".."+string(filepath.Separator)) {
+		return ErrPathEscape
+	}
+	return nil
 }
 
-func loadFiles(files []*afile) (*chart.Chart, error) {
+// loadFiles assembles a chart.Chart out of files, recursing into charts/
+// subdirectories to build subcharts. depth tracks how many charts/ levels
+// have already been descended into, and is checked against
+// opts.MaxSubchartDepth to bound recursion.
+func loadFiles(files []*afile, opts LoadOptions, depth int) (*chart.Chart, error) {
+	if opts.MaxSubchartDepth > 0 && depth > opts.MaxSubchartDepth {
+		return &chart.Chart{}, ErrSubchartTooDeep
+	}
+
 	c := &chart.Chart{}
 	subcharts := map[string][]*afile{}
 
@@ -97,6 +216,11 @@ func loadFiles(files []*afile) (*chart.Chart, error) {
 			return c, errors.New("values.toml is illegal as of 2.0.0-alpha.2")
 		} else if f.name == "values.yaml" {
 			c.Values = &chart.Config{Raw: string(f.data)}
+		} else if f.name == SchemaFileName {
+			if err := validateSchema(f.data); err != nil {
+				return c, fmt.Errorf("invalid %s: %s", SchemaFileName, err)
+			}
+			c.Files = append(c.Files, &any.Any{TypeUrl: f.name, Value: f.data})
 		} else if strings.HasPrefix(f.name, "templates/") {
 			c.Templates = append(c.Templates, &chart.Template{Name: f.name, Data: f.data})
 		} else if strings.HasPrefix(f.name, "charts/") {
@@ -124,7 +248,7 @@ func loadFiles(files []*afile) (*chart.Chart, error) {
 			}
 			// Untar the chart and add to c.Dependencies
 			b := bytes.NewBuffer(file.data)
-			sc, err = LoadArchive(b)
+			sc, err = loadArchiveWithOptions(b, opts, depth+1)
 		} else {
 			// We have to trim the prefix off of every file, and ignore any file
 			// that is in charts/, but isn't actually a chart.
@@ -137,7 +261,7 @@ func loadFiles(files []*afile) (*chart.Chart, error) {
 				f.name = parts[1]
 				buff = append(buff, f)
 			}
-			sc, err = loadFiles(buff)
+			sc, err = loadFiles(buff, opts, depth+1)
 		}
 
 		if err != nil {
@@ -150,7 +274,17 @@ func loadFiles(files []*afile) (*chart.Chart, error) {
 	return c, nil
 }
 
+// DefaultKeyring is the keyring LoadFile uses to verify a chart against a
+// sibling "<name>.prov" file, if one is present. It is nil until a caller
+// sets it, since chartutil has no opinion on where trusted keys come from.
+var DefaultKeyring openpgp.KeyRing
+
 // LoadFile loads from an archive file.
+//
+// If a sibling "<name>.prov" file exists and DefaultKeyring is set, the
+// archive is verified against it via LoadVerified; a failed verification
+// fails the load. If DefaultKeyring is unset, a sibling provenance file is
+// silently ignored, just as if it weren't there.
 func LoadFile(name string) (*chart.Chart, error) {
 	if fi, err := os.Stat(name); err != nil {
 		return nil, err
@@ -158,6 +292,13 @@ func LoadFile(name string) (*chart.Chart, error) {
 		return nil, errors.New("cannot load a directory")
 	}
 
+	if DefaultKeyring != nil {
+		if _, err := os.Stat(name + ".prov"); err == nil {
+			c, _, err := LoadVerified(name, DefaultKeyring)
+			return c, err
+		}
+	}
+
 	raw, err := os.Open(name)
 	if err != nil {
 		return nil, err
@@ -202,5 +343,5 @@ func LoadDir(dir string) (*chart.Chart, error) {
 		return c, err
 	}
 
-	return loadFiles(files)
+	return loadFiles(files, DefaultLoadOptions(), 0)
 }
@@ -0,0 +1,38 @@
+package chartutil
+
+import (
+	"errors"
+	"fmt"
+
+	"k8s.io/helm/pkg/proto/hapi/chart"
+	"k8s.io/helm/pkg/registry"
+)
+
+// ErrUnsupportedMediaType indicates that an OCI manifest does not reference
+// a Helm chart content blob.
+var ErrUnsupportedMediaType = errors.New("unsupported OCI media type for chart content")
+
+// LoadOCI loads a chart that is stored as an artifact in an OCI-compliant
+// registry.
+//
+// ref is the registry reference for the chart, e.g.
+// "myregistry.io/charts/mychart:1.0.0". client resolves ref to a manifest
+// and pulls the underlying blob. The manifest's media type must match
+// registry.ChartMediaType, otherwise ErrUnsupportedMediaType is returned.
+func LoadOCI(ref string, client registry.RegistryClient) (*chart.Chart, error) {
+	_, mediaType, err := client.Resolve(ref)
+	if err != nil {
+		return nil, fmt.Errorf("resolving %s: %s", ref, err)
+	}
+	if mediaType != registry.ChartMediaType {
+		return nil, ErrUnsupportedMediaType
+	}
+
+	blob, err := client.Pull(ref)
+	if err != nil {
+		return nil, fmt.Errorf("pulling %s: %s", ref, err)
+	}
+	defer blob.Close()
+
+	return LoadArchive(blob)
+}
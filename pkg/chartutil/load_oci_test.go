@@ -0,0 +1,98 @@
+package chartutil
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"testing"
+
+	"k8s.io/helm/pkg/registry"
+)
+
+// fakeRegistryClient is an in-memory registry.RegistryClient backed by a map
+// of ref to blob contents and media type.
+type fakeRegistryClient struct {
+	blobs      map[string][]byte
+	mediaTypes map[string]string
+}
+
+func (f *fakeRegistryClient) Pull(ref string) (io.ReadCloser, error) {
+	b, ok := f.blobs[ref]
+	if !ok {
+		return nil, errNotFound(ref)
+	}
+	return ioutil.NopCloser(bytes.NewReader(b)), nil
+}
+
+func (f *fakeRegistryClient) Resolve(ref string) (string, string, error) {
+	_, ok := f.blobs[ref]
+	if !ok {
+		return "", "", errNotFound(ref)
+	}
+	return "sha256:fake", f.mediaTypes[ref], nil
+}
+
+type errNotFound string
+
+func (e errNotFound) Error() string { return "not found: " + string(e) }
+
+func newTestChartBlob(t *testing.T) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+
+	files := []struct {
+		name string
+		data string
+	}{
+		{"testchart/Chart.yaml", "name: testchart\nversion: 1.0.0\n"},
+		{"testchart/values.yaml", "key: value\n"},
+	}
+	for _, f := range files {
+		hdr := &tar.Header{Name: f.name, Mode: 0644, Size: int64(len(f.data))}
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatalf("write header: %s", err)
+		}
+		if _, err := tw.Write([]byte(f.data)); err != nil {
+			t.Fatalf("write data: %s", err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatalf("close tar: %s", err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatalf("close gzip: %s", err)
+	}
+	return buf.Bytes()
+}
+
+func TestLoadOCI(t *testing.T) {
+	ref := "myregistry.io/charts/testchart:1.0.0"
+	client := &fakeRegistryClient{
+		blobs:      map[string][]byte{ref: newTestChartBlob(t)},
+		mediaTypes: map[string]string{ref: registry.ChartMediaType},
+	}
+
+	c, err := LoadOCI(ref, client)
+	if err != nil {
+		t.Fatalf("error loading chart: %s", err)
+	}
+	if c.Metadata.Name != "testchart" {
+		t.Errorf("expected chart name 'testchart', got %q", c.Metadata.Name)
+	}
+}
+
+func TestLoadOCI_WrongMediaType(t *testing.T) {
+	ref := "myregistry.io/charts/testchart:1.0.0"
+	client := &fakeRegistryClient{
+		blobs:      map[string][]byte{ref: newTestChartBlob(t)},
+		mediaTypes: map[string]string{ref: "application/vnd.oci.image.manifest.v1+json"},
+	}
+
+	if _, err := LoadOCI(ref, client); err != ErrUnsupportedMediaType {
+		t.Errorf("expected ErrUnsupportedMediaType, got %v", err)
+	}
+}
@@ -0,0 +1,96 @@
+package chartutil
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"strings"
+	"testing"
+)
+
+func buildArchive(t *testing.T, files map[string]string) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for name, data := range files {
+		hdr := &tar.Header{Name: "testchart/" + name, Mode: 0644, Size: int64(len(data))}
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatalf("write header: %s", err)
+		}
+		if _, err := tw.Write([]byte(data)); err != nil {
+			t.Fatalf("write data: %s", err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatalf("close tar: %s", err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatalf("close gzip: %s", err)
+	}
+	return &buf
+}
+
+func TestLoadArchiveWithOptions_MaxFileSize(t *testing.T) {
+	buf := buildArchive(t, map[string]string{
+		"Chart.yaml": "name: testchart\nversion: 1.0.0\n",
+		"big.txt":    strings.Repeat("a", 1024),
+	})
+
+	opts := DefaultLoadOptions()
+	opts.MaxFileSize = 100
+
+	if _, err := LoadArchiveWithOptions(buf, opts); err != ErrArchiveTooLarge {
+		t.Errorf("expected ErrArchiveTooLarge, got %v", err)
+	}
+}
+
+func TestLoadArchiveWithOptions_MaxFiles(t *testing.T) {
+	buf := buildArchive(t, map[string]string{
+		"Chart.yaml": "name: testchart\nversion: 1.0.0\n",
+		"a.txt":      "a",
+		"b.txt":      "b",
+	})
+
+	opts := DefaultLoadOptions()
+	opts.MaxFiles = 1
+
+	if _, err := LoadArchiveWithOptions(buf, opts); err != ErrTooManyFiles {
+		t.Errorf("expected ErrTooManyFiles, got %v", err)
+	}
+}
+
+func TestLoadArchiveWithOptions_PathEscape(t *testing.T) {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	data := "owned"
+	hdr := &tar.Header{Name: "testchart/../../etc/passwd", Mode: 0644, Size: int64(len(data))}
+	if err := tw.WriteHeader(hdr); err != nil {
+		t.Fatalf("write header: %s", err)
+	}
+	if _, err := tw.Write([]byte(data)); err != nil {
+		t.Fatalf("write data: %s", err)
+	}
+	tw.Close()
+	gz.Close()
+
+	if _, err := LoadArchiveWithOptions(&buf, DefaultLoadOptions()); err != ErrPathEscape {
+		t.Errorf("expected ErrPathEscape, got %v", err)
+	}
+}
+
+func TestLoadArchiveWithOptions_Defaults(t *testing.T) {
+	buf := buildArchive(t, map[string]string{
+		"Chart.yaml":  "name: testchart\nversion: 1.0.0\n",
+		"values.yaml": "key: value\n",
+	})
+
+	c, err := LoadArchive(buf)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if c.Metadata.Name != "testchart" {
+		t.Errorf("expected chart name 'testchart', got %q", c.Metadata.Name)
+	}
+}
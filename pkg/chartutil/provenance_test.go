@@ -0,0 +1,76 @@
+package chartutil
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	"golang.org/x/crypto/openpgp"
+	"golang.org/x/crypto/openpgp/clearsign"
+)
+
+func newTestKeyring(t *testing.T) (openpgp.KeyRing, *openpgp.Entity) {
+	t.Helper()
+	entity, err := openpgp.NewEntity("Test Signer", "", "test@example.com", nil)
+	if err != nil {
+		t.Fatalf("generating test key: %s", err)
+	}
+	return openpgp.EntityList{entity}, entity
+}
+
+func signProvenance(t *testing.T, entity *openpgp.Entity, hash string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w, err := clearsign.Encode(&buf, entity.PrivateKey, nil)
+	if err != nil {
+		t.Fatalf("encoding clearsign: %s", err)
+	}
+	if _, err := w.Write([]byte("sha256: " + hash + "\n")); err != nil {
+		t.Fatalf("writing provenance body: %s", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("closing clearsign writer: %s", err)
+	}
+	return buf.Bytes()
+}
+
+func TestVerifyProvenance(t *testing.T) {
+	keyring, entity := newTestKeyring(t)
+	archive := []byte("pretend this is a tar.gz chart archive")
+	sum := sha256.Sum256(archive)
+	hash := hex.EncodeToString(sum[:])
+
+	provData := signProvenance(t, entity, hash)
+
+	prov, err := verifyProvenance(archive, provData, keyring)
+	if err != nil {
+		t.Fatalf("expected verification to succeed, got %s", err)
+	}
+	if prov.Hash != hash {
+		t.Errorf("expected hash %s, got %s", hash, prov.Hash)
+	}
+}
+
+func TestVerifyProvenance_HashMismatch(t *testing.T) {
+	keyring, entity := newTestKeyring(t)
+	archive := []byte("pretend this is a tar.gz chart archive")
+	provData := signProvenance(t, entity, "0000000000000000000000000000000000000000000000000000000000000000")
+
+	if _, err := verifyProvenance(archive, provData, keyring); err != ErrProvenanceFailed {
+		t.Errorf("expected ErrProvenanceFailed, got %v", err)
+	}
+}
+
+func TestVerifyProvenance_UntrustedSigner(t *testing.T) {
+	_, otherEntity := newTestKeyring(t)
+	archive := []byte("pretend this is a tar.gz chart archive")
+	sum := sha256.Sum256(archive)
+	hash := hex.EncodeToString(sum[:])
+	provData := signProvenance(t, otherEntity, hash)
+
+	emptyKeyring := openpgp.EntityList{}
+	if _, err := verifyProvenance(archive, provData, emptyKeyring); err != ErrProvenanceFailed {
+		t.Errorf("expected ErrProvenanceFailed, got %v", err)
+	}
+}
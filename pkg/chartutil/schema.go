@@ -0,0 +1,30 @@
+package chartutil
+
+import (
+	"github.com/xeipuuv/gojsonschema"
+
+	"k8s.io/helm/pkg/proto/hapi/chart"
+)
+
+// SchemaFileName is the recognized filename for a chart's JSON Schema
+// (draft-07), used to validate user-supplied values before rendering.
+const SchemaFileName = "values.schema.json"
+
+// ChartSchema returns the raw values.schema.json document bundled with c, or
+// nil if the chart carries none.
+func ChartSchema(c *chart.Chart) []byte {
+	for _, f := range c.Files {
+		if f.TypeUrl == SchemaFileName {
+			return f.Value
+		}
+	}
+	return nil
+}
+
+// validateSchema confirms that data is itself a well-formed JSON Schema
+// document, so that a chart with a broken schema is rejected at load time
+// rather than failing confusingly later, at values validation time.
+func validateSchema(data []byte) error {
+	_, err := gojsonschema.NewSchema(gojsonschema.NewBytesLoader(data))
+	return err
+}
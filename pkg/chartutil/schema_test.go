@@ -0,0 +1,31 @@
+package chartutil
+
+import "testing"
+
+func TestLoadArchive_RejectsMalformedSchema(t *testing.T) {
+	buf := buildArchive(t, map[string]string{
+		"Chart.yaml":   "name: testchart\nversion: 1.0.0\n",
+		SchemaFileName: "{not valid json",
+		"values.yaml":  "replicaCount: 1\n",
+	})
+
+	if _, err := LoadArchive(buf); err == nil {
+		t.Fatal("expected an error loading a chart with a malformed values.schema.json")
+	}
+}
+
+func TestChartSchema(t *testing.T) {
+	schema := `{"type": "object"}`
+	buf := buildArchive(t, map[string]string{
+		"Chart.yaml":   "name: testchart\nversion: 1.0.0\n",
+		SchemaFileName: schema,
+	})
+
+	c, err := LoadArchive(buf)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if got := string(ChartSchema(c)); got != schema {
+		t.Errorf("expected schema %q, got %q", schema, got)
+	}
+}
@@ -0,0 +1,32 @@
+/*
+Package registry provides the types needed to load chart archives from
+OCI-compliant artifact registries.
+
+It intentionally stops at the interface boundary: callers supply a
+RegistryClient backed by whatever OCI client they prefer (Docker/Distribution,
+ORAS, or a test fake), and chartutil.LoadOCI takes care of validating and
+unpacking the result.
+*/
+package registry
+
+import "io"
+
+// ChartMediaType is the OCI media type that identifies a Helm chart content
+// blob, as opposed to other artifact types a registry may store.
+const ChartMediaType = "application/vnd.cncf.helm.chart.content.v1.tar+gzip"
+
+// RegistryClient pulls and resolves artifacts from an OCI-compliant
+// artifact registry.
+//
+// Implementations may wrap Docker/Distribution, ORAS, or any other backend
+// that speaks the OCI distribution spec.
+type RegistryClient interface {
+	// Pull fetches the blob referenced by ref and returns a stream of its
+	// raw (compressed) contents. Callers are responsible for closing the
+	// returned ReadCloser.
+	Pull(ref string) (io.ReadCloser, error)
+
+	// Resolve returns the digest and media type of the manifest referenced
+	// by ref, without fetching the blob itself.
+	Resolve(ref string) (digest, mediaType string, err error)
+}
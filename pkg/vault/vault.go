@@ -0,0 +1,51 @@
+/*
+Package vault provides the types needed to resolve ${vault:...} secret
+placeholders found in chart values.
+
+It intentionally stops at the interface boundary: callers supply a
+SecretResolver backed by whatever Vault client (or other remote secret
+store) they prefer, and pkg/chart's VaultValuesDecoder takes care of finding
+and substituting placeholders.
+*/
+package vault
+
+import "sync"
+
+// SecretResolver resolves a secret reference, such as "secret/path#field",
+// to its plaintext value.
+type SecretResolver interface {
+	Resolve(ref string) (string, error)
+}
+
+// CachingResolver wraps a SecretResolver so that a given reference is
+// fetched from the backing store at most once per process, rather than once
+// per occurrence in a values file.
+type CachingResolver struct {
+	resolver SecretResolver
+
+	mu    sync.Mutex
+	cache map[string]string
+}
+
+// NewCachingResolver wraps resolver with an in-memory cache.
+func NewCachingResolver(resolver SecretResolver) *CachingResolver {
+	return &CachingResolver{resolver: resolver, cache: map[string]string{}}
+}
+
+// Resolve returns the cached value for ref, resolving and caching it via the
+// wrapped SecretResolver on a cache miss.
+func (c *CachingResolver) Resolve(ref string) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if v, ok := c.cache[ref]; ok {
+		return v, nil
+	}
+
+	v, err := c.resolver.Resolve(ref)
+	if err != nil {
+		return "", err
+	}
+	c.cache[ref] = v
+	return v, nil
+}